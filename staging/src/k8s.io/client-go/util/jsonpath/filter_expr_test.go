@@ -0,0 +1,68 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import "testing"
+
+// TestFilterNodeExprTree verifies that FilterNode.Expr can hold a full
+// expression tree built from BinaryOpNode, UnaryOpNode, ParenNode and
+// FunctionCallNode, as required to represent a filter such as
+// `@.status.phase=="Running" && @.spec.replicas>1`.
+func TestFilterNodeExprTree(t *testing.T) {
+	phase := newBinaryOp(0, "==", newField(0, "phase", nil), newText(0, "Running", nil), nil)
+	replicas := newBinaryOp(0, ">", newField(0, "replicas", nil), newInt(0, 1, nil), nil)
+	and := newBinaryOp(0, "&&", newParen(0, phase, nil), replicas, nil)
+
+	filter := newFilter(0, and, nil)
+
+	got, ok := filter.Expr.(*BinaryOpNode)
+	if !ok {
+		t.Fatalf("FilterNode.Expr = %T, want *BinaryOpNode", filter.Expr)
+	}
+	if got.Operator != "&&" {
+		t.Fatalf("root operator = %q, want &&", got.Operator)
+	}
+	if _, ok := got.Left.(*ParenNode); !ok {
+		t.Fatalf("left operand = %T, want *ParenNode", got.Left)
+	}
+}
+
+func TestFunctionCallNodeInFilter(t *testing.T) {
+	call := newFunctionCall(0, "match", []Node{
+		newField(0, "name", nil),
+		newText(0, "^web-", nil),
+	}, nil)
+	filter := newFilter(0, call, nil)
+
+	got, ok := filter.Expr.(*FunctionCallNode)
+	if !ok {
+		t.Fatalf("FilterNode.Expr = %T, want *FunctionCallNode", filter.Expr)
+	}
+	if got.Name != "match" || len(got.Args) != 2 {
+		t.Fatalf("got FunctionCallNode %+v, want match() with 2 args", got)
+	}
+}
+
+func TestUnaryOpNodeCopy(t *testing.T) {
+	orig := newUnaryOp(0, "!", newField(0, "a", nil), nil)
+	dup := orig.Copy().(*UnaryOpNode)
+	dup.Node.(*FieldNode).Value = "b"
+
+	if orig.Node.(*FieldNode).Value != "a" {
+		t.Fatalf("Copy() shared the operand with the original: got %q, want a", orig.Node.(*FieldNode).Value)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Format renders node as canonical JSONPath source text, such that
+// re-parsing the result produces an equivalent tree. Unlike String(),
+// which prints a debug form for inspection, Format is meant to be
+// consumed by other tools (e.g. to round-trip a parsed and rewritten
+// expression, or to normalize a query for logging).
+func Format(node Node) string {
+	var buf bytes.Buffer
+	writeTo(&buf, node)
+	return buf.String()
+}
+
+func writeTo(buf *bytes.Buffer, node Node) {
+	switch n := node.(type) {
+	case *ListNode:
+		for _, child := range n.Nodes {
+			writeTo(buf, child)
+		}
+	case *TextNode:
+		buf.WriteString(strconv.Quote(n.Text))
+	case *FieldNode:
+		buf.WriteByte('.')
+		buf.WriteString(n.Value)
+	case *IdentifierNode:
+		buf.WriteString(n.Name)
+	case *ArrayNode:
+		buf.WriteByte('[')
+		writeArrayParams(buf, n.Params)
+		buf.WriteByte(']')
+	case *FilterNode:
+		buf.WriteString("[?(")
+		if n.Expr != nil {
+			writeTo(buf, n.Expr)
+		}
+		buf.WriteString(")]")
+	case *IntNode:
+		buf.WriteString(strconv.Itoa(n.Value))
+	case *FloatNode:
+		buf.WriteString(strconv.FormatFloat(n.Value, 'g', -1, 64))
+	case *WildcardNode:
+		buf.WriteByte('*')
+	case *RecursiveNode:
+		buf.WriteString("..")
+	case *UnionNode:
+		buf.WriteByte('[')
+		for i, elem := range n.Nodes {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeTo(buf, elem)
+		}
+		buf.WriteByte(']')
+	case *BoolNode:
+		buf.WriteString(strconv.FormatBool(n.Value))
+	case *ParentNode:
+		buf.WriteByte('$')
+	case *BinaryOpNode:
+		writeTo(buf, n.Left)
+		buf.WriteString(n.Operator)
+		writeTo(buf, n.Right)
+	case *UnaryOpNode:
+		buf.WriteString(n.Operator)
+		writeTo(buf, n.Node)
+	case *ParenNode:
+		buf.WriteByte('(')
+		writeTo(buf, n.Node)
+		buf.WriteByte(')')
+	case *FunctionCallNode:
+		buf.WriteString(n.Name)
+		buf.WriteByte('(')
+		for i, arg := range n.Args {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeTo(buf, arg)
+		}
+		buf.WriteByte(')')
+	default:
+		fmt.Fprintf(buf, "%s", node)
+	}
+}
+
+// writeArrayParams renders start:end:step, omitting whichever trailing
+// fields aren't significant: a bare index (only start known) is written
+// without any colons, and the step segment is only written when the step
+// itself is known.
+func writeArrayParams(buf *bytes.Buffer, params [3]ParamsEntry) {
+	start, end, step := params[0], params[1], params[2]
+	if !end.Known && !step.Known {
+		if start.Known {
+			buf.WriteString(strconv.Itoa(start.Value))
+		}
+		return
+	}
+	if start.Known {
+		buf.WriteString(strconv.Itoa(start.Value))
+	}
+	buf.WriteByte(':')
+	if end.Known {
+		buf.WriteString(strconv.Itoa(end.Value))
+	}
+	if step.Known {
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(step.Value))
+	}
+}
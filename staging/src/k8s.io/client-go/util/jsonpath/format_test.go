@@ -0,0 +1,111 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import "testing"
+
+func TestFormatField(t *testing.T) {
+	root := newList(0, nil)
+	root.append(newParent(0, nil))
+	root.append(newField(0, "metadata", nil))
+	root.append(newField(0, "name", nil))
+
+	if got, want := Format(root), "$.metadata.name"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatArraySlice(t *testing.T) {
+	array := newArray(0, [3]ParamsEntry{
+		{Value: 0, Known: true},
+		{Value: 5, Known: true},
+		{Value: 2, Known: true},
+	}, nil)
+
+	if got, want := Format(array), "[0:5:2]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatArrayIndex(t *testing.T) {
+	array := newArray(0, [3]ParamsEntry{{Value: 5, Known: true}, {}, {}}, nil)
+
+	if got, want := Format(array), "[5]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatArrayTwoBoundSlice(t *testing.T) {
+	array := newArray(0, [3]ParamsEntry{{Value: 2, Known: true}, {Value: 5, Known: true}, {}}, nil)
+
+	if got, want := Format(array), "[2:5]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFilterExpr(t *testing.T) {
+	filter := newFilter(0, newBinaryOp(0, "==", newField(0, "a", nil), newInt(0, 1, nil), nil), nil)
+
+	if got, want := Format(filter), "[?(.a==1)]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatFilterStringLiteral(t *testing.T) {
+	filter := newFilter(0, newBinaryOp(0, "==", newField(0, "phase", nil), newText(0, "Running", nil), nil), nil)
+
+	if got, want := Format(filter), `[?(.phase=="Running")]`; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatUnion(t *testing.T) {
+	a := newList(0, nil)
+	a.append(newField(0, "a", nil))
+	b := newList(0, nil)
+	b.append(newField(0, "b", nil))
+	union := newUnion(0, []*ListNode{a, b}, nil)
+
+	if got, want := Format(union), "[.a,.b]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+// TestFormatFilterNilExpr is a regression test: writeTo used to recurse
+// unconditionally into a FilterNode's expression, so a FilterNode with no
+// Expr attached yet was rendered with the fallback "%!s(<nil>)" text
+// between the filter brackets.
+func TestFormatFilterNilExpr(t *testing.T) {
+	filter := newFilter(0, nil, nil)
+
+	if got, want := Format(filter), "[?()]"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatCopyMatchesOriginal(t *testing.T) {
+	root := newList(0, nil)
+	root.append(newParent(0, nil))
+	root.append(newRecursive(0, nil))
+	root.append(newField(0, "spec", nil))
+
+	first := Format(root)
+	second := Format(root.Copy())
+	if first != second {
+		t.Errorf("formatting a node and its Copy() produced different source: %q != %q", first, second)
+	}
+}
@@ -44,39 +44,66 @@ const (
 	NodeRecursive
 	NodeUnion
 	NodeBool
+	NodeBinaryOp
+	NodeUnaryOp
+	NodeParen
+	NodeFunctionCall
 )
 
 var NodeTypeName = map[NodeType]string{
-	NodeText:       "NodeText",
-	NodeArray:      "NodeArray",
-	NodeList:       "NodeList",
-	NodeField:      "NodeField",
-	NodeParent:     "NodeParent",
-	NodeIdentifier: "NodeIdentifier",
-	NodeFilter:     "NodeFilter",
-	NodeInt:        "NodeInt",
-	NodeFloat:      "NodeFloat",
-	NodeWildcard:   "NodeWildcard",
-	NodeRecursive:  "NodeRecursive",
-	NodeUnion:      "NodeUnion",
-	NodeBool:       "NodeBool",
+	NodeText:         "NodeText",
+	NodeArray:        "NodeArray",
+	NodeList:         "NodeList",
+	NodeField:        "NodeField",
+	NodeParent:       "NodeParent",
+	NodeIdentifier:   "NodeIdentifier",
+	NodeFilter:       "NodeFilter",
+	NodeInt:          "NodeInt",
+	NodeFloat:        "NodeFloat",
+	NodeWildcard:     "NodeWildcard",
+	NodeRecursive:    "NodeRecursive",
+	NodeUnion:        "NodeUnion",
+	NodeBool:         "NodeBool",
+	NodeBinaryOp:     "NodeBinaryOp",
+	NodeUnaryOp:      "NodeUnaryOp",
+	NodeParen:        "NodeParen",
+	NodeFunctionCall: "NodeFunctionCall",
+}
+
+// Pos represents a byte position in the original JSONPath text from which
+// this parse tree was created.
+type Pos int
+
+// Position returns itself and provides an easy default implementation
+// for embedding in a Node.
+func (p Pos) Position() Pos {
+	return p
 }
 
 type Node interface {
 	Type() NodeType
 	String() string
+	// Position returns the byte offset, within the original JSONPath
+	// text, at which this node begins.
+	Position() Pos
+	// Copy returns a deep copy of the node, so that the result may be
+	// mutated or reused (e.g. across goroutines) without affecting the
+	// original.
+	Copy() Node
 }
 
 // ListNode holds a sequence of nodes.
 type ListNode struct {
 	NodeType
+	Pos
 	Nodes  []Node // The element nodes in lexical order.
 	Parent interface{}
 }
 
-func newList(parent interface{}) *ListNode {
+func newList(pos Pos, parent interface{}) *ListNode {
 	return &ListNode{
 		NodeType: NodeList,
+		Pos:      pos,
 		Parent:   parent,
 	}
 }
@@ -89,16 +116,29 @@ func (l *ListNode) String() string {
 	return l.Type().String()
 }
 
+func (l *ListNode) Copy() Node {
+	if l == nil {
+		return l
+	}
+	n := newList(l.Pos, l.Parent)
+	for _, elem := range l.Nodes {
+		n.append(elem.Copy())
+	}
+	return n
+}
+
 // TextNode holds plain text.
 type TextNode struct {
 	NodeType
+	Pos
 	Text   string // The text; may span newlines.
 	Parent interface{}
 }
 
-func newText(text string, parent interface{}) *TextNode {
+func newText(pos Pos, text string, parent interface{}) *TextNode {
 	return &TextNode{
 		NodeType: NodeText,
+		Pos:      pos,
 		Text:     text,
 		Parent:   parent,
 	}
@@ -108,16 +148,22 @@ func (t *TextNode) String() string {
 	return fmt.Sprintf("%s: %s", t.Type(), t.Text)
 }
 
+func (t *TextNode) Copy() Node {
+	return newText(t.Pos, t.Text, t.Parent)
+}
+
 // FieldNode holds field of struct
 type FieldNode struct {
 	NodeType
+	Pos
 	Value  string
 	Parent interface{}
 }
 
-func newField(value string, parent interface{}) *FieldNode {
+func newField(pos Pos, value string, parent interface{}) *FieldNode {
 	return &FieldNode{
 		NodeType: NodeField,
+		Pos:      pos,
 		Value:    value,
 		Parent:   parent,
 	}
@@ -127,16 +173,22 @@ func (f *FieldNode) String() string {
 	return fmt.Sprintf("%s: %s", f.Type(), f.Value)
 }
 
+func (f *FieldNode) Copy() Node {
+	return newField(f.Pos, f.Value, f.Parent)
+}
+
 // IdentifierNode holds an identifier
 type IdentifierNode struct {
 	NodeType
+	Pos
 	Name   string
 	Parent interface{}
 }
 
-func newIdentifier(value string, parent interface{}) *IdentifierNode {
+func newIdentifier(pos Pos, value string, parent interface{}) *IdentifierNode {
 	return &IdentifierNode{
 		NodeType: NodeIdentifier,
+		Pos:      pos,
 		Name:     value,
 		Parent:   parent,
 	}
@@ -146,6 +198,10 @@ func (f *IdentifierNode) String() string {
 	return fmt.Sprintf("%s: %s", f.Type(), f.Name)
 }
 
+func (f *IdentifierNode) Copy() Node {
+	return newIdentifier(f.Pos, f.Name, f.Parent)
+}
+
 // ParamsEntry holds param information for ArrayNode
 type ParamsEntry struct {
 	Value   int
@@ -156,13 +212,15 @@ type ParamsEntry struct {
 // ArrayNode holds start, end, step information for array index selection
 type ArrayNode struct {
 	NodeType
+	Pos
 	Params [3]ParamsEntry // start, end, step
 	Parent interface{}
 }
 
-func newArray(params [3]ParamsEntry, parent interface{}) *ArrayNode {
+func newArray(pos Pos, params [3]ParamsEntry, parent interface{}) *ArrayNode {
 	return &ArrayNode{
 		NodeType: NodeArray,
+		Pos:      pos,
 		Params:   params,
 		Parent:   parent,
 	}
@@ -172,39 +230,55 @@ func (a *ArrayNode) String() string {
 	return fmt.Sprintf("%s: %v", a.Type(), a.Params)
 }
 
-// FilterNode holds operand and operator information for filter
+func (a *ArrayNode) Copy() Node {
+	return newArray(a.Pos, a.Params, a.Parent)
+}
+
+// FilterNode holds the expression evaluated for a filter, such as
+// `@.status.phase=="Running" && @.spec.replicas>1`. Expr is the root of the
+// expression tree and is built from BinaryOpNode, UnaryOpNode, ParenNode,
+// FunctionCallNode and the ordinary value/path nodes (FieldNode, IntNode,
+// and so on).
 type FilterNode struct {
 	NodeType
-	Left     *ListNode
-	Right    *ListNode
-	Operator string
-	Parent   interface{}
+	Pos
+	Expr   Node
+	Parent interface{}
 }
 
-func newFilter(left, right *ListNode, operator string, parent interface{}) *FilterNode {
+func newFilter(pos Pos, expr Node, parent interface{}) *FilterNode {
 	return &FilterNode{
 		NodeType: NodeFilter,
-		Left:     left,
-		Right:    right,
-		Operator: operator,
+		Pos:      pos,
+		Expr:     expr,
 		Parent:   parent,
 	}
 }
 
 func (f *FilterNode) String() string {
-	return fmt.Sprintf("%s: %s %s %s", f.Type(), f.Left, f.Operator, f.Right)
+	return fmt.Sprintf("%s: %s", f.Type(), f.Expr)
+}
+
+func (f *FilterNode) Copy() Node {
+	n := newFilter(f.Pos, nil, f.Parent)
+	if f.Expr != nil {
+		n.Expr = f.Expr.Copy()
+	}
+	return n
 }
 
 // IntNode holds integer value
 type IntNode struct {
 	NodeType
+	Pos
 	Value  int
 	Parent interface{}
 }
 
-func newInt(num int, parent interface{}) *IntNode {
+func newInt(pos Pos, num int, parent interface{}) *IntNode {
 	return &IntNode{
 		NodeType: NodeInt,
+		Pos:      pos,
 		Value:    num,
 		Parent:   parent,
 	}
@@ -214,16 +288,22 @@ func (i *IntNode) String() string {
 	return fmt.Sprintf("%s: %d", i.Type(), i.Value)
 }
 
+func (i *IntNode) Copy() Node {
+	return newInt(i.Pos, i.Value, i.Parent)
+}
+
 // FloatNode holds float value
 type FloatNode struct {
 	NodeType
+	Pos
 	Value  float64
 	Parent interface{}
 }
 
-func newFloat(num float64, parent interface{}) *FloatNode {
+func newFloat(pos Pos, num float64, parent interface{}) *FloatNode {
 	return &FloatNode{
 		NodeType: NodeFloat,
+		Pos:      pos,
 		Value:    num,
 		Parent:   parent,
 	}
@@ -233,15 +313,21 @@ func (i *FloatNode) String() string {
 	return fmt.Sprintf("%s: %f", i.Type(), i.Value)
 }
 
+func (i *FloatNode) Copy() Node {
+	return newFloat(i.Pos, i.Value, i.Parent)
+}
+
 // WildcardNode means a wildcard
 type WildcardNode struct {
 	NodeType
+	Pos
 	Parent interface{}
 }
 
-func newWildcard(parent interface{}) *WildcardNode {
+func newWildcard(pos Pos, parent interface{}) *WildcardNode {
 	return &WildcardNode{
 		NodeType: NodeWildcard,
+		Pos:      pos,
 		Parent:   parent,
 	}
 }
@@ -250,15 +336,21 @@ func (i *WildcardNode) String() string {
 	return i.Type().String()
 }
 
+func (i *WildcardNode) Copy() Node {
+	return newWildcard(i.Pos, i.Parent)
+}
+
 // RecursiveNode means a recursive descent operator
 type RecursiveNode struct {
 	NodeType
+	Pos
 	Parent interface{}
 }
 
-func newRecursive(parent interface{}) *RecursiveNode {
+func newRecursive(pos Pos, parent interface{}) *RecursiveNode {
 	return &RecursiveNode{
 		NodeType: NodeRecursive,
+		Pos:      pos,
 		Parent:   parent,
 	}
 }
@@ -267,16 +359,22 @@ func (r *RecursiveNode) String() string {
 	return r.Type().String()
 }
 
+func (r *RecursiveNode) Copy() Node {
+	return newRecursive(r.Pos, r.Parent)
+}
+
 // UnionNode is union of ListNode
 type UnionNode struct {
 	NodeType
+	Pos
 	Nodes  []*ListNode
 	Parent interface{}
 }
 
-func newUnion(nodes []*ListNode, parent interface{}) *UnionNode {
+func newUnion(pos Pos, nodes []*ListNode, parent interface{}) *UnionNode {
 	return &UnionNode{
 		NodeType: NodeUnion,
+		Pos:      pos,
 		Nodes:    nodes,
 		Parent:   parent,
 	}
@@ -286,16 +384,26 @@ func (u *UnionNode) String() string {
 	return u.Type().String()
 }
 
+func (u *UnionNode) Copy() Node {
+	nodes := make([]*ListNode, len(u.Nodes))
+	for i, elem := range u.Nodes {
+		nodes[i] = elem.Copy().(*ListNode)
+	}
+	return newUnion(u.Pos, nodes, u.Parent)
+}
+
 // BoolNode holds bool value
 type BoolNode struct {
 	NodeType
+	Pos
 	Value  bool
 	Parent interface{}
 }
 
-func newBool(value bool, parent interface{}) *BoolNode {
+func newBool(pos Pos, value bool, parent interface{}) *BoolNode {
 	return &BoolNode{
 		NodeType: NodeBool,
+		Pos:      pos,
 		Value:    value,
 		Parent:   parent,
 	}
@@ -305,15 +413,21 @@ func (b *BoolNode) String() string {
 	return fmt.Sprintf("%s: %t", b.Type(), b.Value)
 }
 
+func (b *BoolNode) Copy() Node {
+	return newBool(b.Pos, b.Value, b.Parent)
+}
+
 // ParentNode
 type ParentNode struct {
 	NodeType
+	Pos
 	Parent interface{}
 }
 
-func newParent(parent interface{}) *ParentNode {
+func newParent(pos Pos, parent interface{}) *ParentNode {
 	return &ParentNode{
 		NodeType: NodeParent,
+		Pos:      pos,
 		Parent:   parent,
 	}
 }
@@ -321,3 +435,125 @@ func newParent(parent interface{}) *ParentNode {
 func (p *ParentNode) String() string {
 	return fmt.Sprintf("%s: %t", p.Type(), p.Parent)
 }
+
+func (p *ParentNode) Copy() Node {
+	return newParent(p.Pos, p.Parent)
+}
+
+// BinaryOpNode holds a binary expression used inside a filter, such as
+// `@.a==1` or `@.a>1 && @.b<2`. Supported operators are `&&`, `||`, `==`,
+// `!=`, `<`, `<=`, `>`, `>=` and `=~` (regular expression match).
+type BinaryOpNode struct {
+	NodeType
+	Pos
+	Operator string
+	Left     Node
+	Right    Node
+	Parent   interface{}
+}
+
+func newBinaryOp(pos Pos, operator string, left, right Node, parent interface{}) *BinaryOpNode {
+	return &BinaryOpNode{
+		NodeType: NodeBinaryOp,
+		Pos:      pos,
+		Operator: operator,
+		Left:     left,
+		Right:    right,
+		Parent:   parent,
+	}
+}
+
+func (b *BinaryOpNode) String() string {
+	return fmt.Sprintf("%s: %s %s %s", b.Type(), b.Left, b.Operator, b.Right)
+}
+
+func (b *BinaryOpNode) Copy() Node {
+	return newBinaryOp(b.Pos, b.Operator, b.Left.Copy(), b.Right.Copy(), b.Parent)
+}
+
+// UnaryOpNode holds a unary expression used inside a filter, such as the
+// negation `!@.a`.
+type UnaryOpNode struct {
+	NodeType
+	Pos
+	Operator string
+	Node     Node
+	Parent   interface{}
+}
+
+func newUnaryOp(pos Pos, operator string, node Node, parent interface{}) *UnaryOpNode {
+	return &UnaryOpNode{
+		NodeType: NodeUnaryOp,
+		Pos:      pos,
+		Operator: operator,
+		Node:     node,
+		Parent:   parent,
+	}
+}
+
+func (u *UnaryOpNode) String() string {
+	return fmt.Sprintf("%s: %s%s", u.Type(), u.Operator, u.Node)
+}
+
+func (u *UnaryOpNode) Copy() Node {
+	return newUnaryOp(u.Pos, u.Operator, u.Node.Copy(), u.Parent)
+}
+
+// ParenNode holds a parenthesized expression, preserved so that the
+// original grouping can be recovered (e.g. when formatting the tree back
+// to source).
+type ParenNode struct {
+	NodeType
+	Pos
+	Node   Node
+	Parent interface{}
+}
+
+func newParen(pos Pos, node Node, parent interface{}) *ParenNode {
+	return &ParenNode{
+		NodeType: NodeParen,
+		Pos:      pos,
+		Node:     node,
+		Parent:   parent,
+	}
+}
+
+func (p *ParenNode) String() string {
+	return fmt.Sprintf("%s: (%s)", p.Type(), p.Node)
+}
+
+func (p *ParenNode) Copy() Node {
+	return newParen(p.Pos, p.Node.Copy(), p.Parent)
+}
+
+// FunctionCallNode holds a call to a built-in filter function, such as
+// `length(@.items)` or `match(@.metadata.name, "^web-")`.
+type FunctionCallNode struct {
+	NodeType
+	Pos
+	Name   string
+	Args   []Node
+	Parent interface{}
+}
+
+func newFunctionCall(pos Pos, name string, args []Node, parent interface{}) *FunctionCallNode {
+	return &FunctionCallNode{
+		NodeType: NodeFunctionCall,
+		Pos:      pos,
+		Name:     name,
+		Args:     args,
+		Parent:   parent,
+	}
+}
+
+func (c *FunctionCallNode) String() string {
+	return fmt.Sprintf("%s: %s%v", c.Type(), c.Name, c.Args)
+}
+
+func (c *FunctionCallNode) Copy() Node {
+	args := make([]Node, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.Copy()
+	}
+	return newFunctionCall(c.Pos, c.Name, args, c.Parent)
+}
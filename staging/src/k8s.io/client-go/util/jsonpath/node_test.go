@@ -0,0 +1,74 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import "testing"
+
+func TestNodePosition(t *testing.T) {
+	nodes := []Node{
+		newText(5, "abc", nil),
+		newField(5, "metadata", nil),
+		newIdentifier(5, "x", nil),
+		newInt(5, 1, nil),
+		newFloat(5, 1.5, nil),
+		newWildcard(5, nil),
+		newRecursive(5, nil),
+		newBool(5, true, nil),
+		newParent(5, nil),
+	}
+	for _, n := range nodes {
+		if got := n.Position(); got != Pos(5) {
+			t.Errorf("%s: Position() = %d, want 5", n.Type(), got)
+		}
+	}
+}
+
+func TestListNodeCopyIsIndependent(t *testing.T) {
+	orig := newList(0, nil)
+	orig.append(newField(1, "a", nil))
+
+	dup := orig.Copy().(*ListNode)
+	dup.append(newField(2, "b", nil))
+
+	if len(orig.Nodes) != 1 {
+		t.Fatalf("mutating the copy affected the original: got %d nodes, want 1", len(orig.Nodes))
+	}
+	if len(dup.Nodes) != 2 {
+		t.Fatalf("copy should have both the copied and the new node: got %d nodes, want 2", len(dup.Nodes))
+	}
+}
+
+func TestFilterNodeCopyNilExpr(t *testing.T) {
+	orig := newFilter(0, nil, nil)
+
+	dup := orig.Copy().(*FilterNode)
+	if dup.Expr != nil {
+		t.Fatalf("Copy() of a FilterNode with nil Expr should stay nil, got %v", dup.Expr)
+	}
+}
+
+func TestFilterNodeCopyIsDeep(t *testing.T) {
+	orig := newFilter(0, newBinaryOp(1, "==", newField(2, "a", nil), newInt(3, 1, nil), nil), nil)
+
+	dup := orig.Copy().(*FilterNode)
+	dup.Expr.(*BinaryOpNode).Operator = "!="
+
+	origBin := orig.Expr.(*BinaryOpNode)
+	if origBin.Operator != "==" {
+		t.Fatalf("mutating the copy's expression affected the original: got %q, want ==", origBin.Operator)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+// Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// children returns the direct child nodes of node, in lexical order.
+func children(node Node) []Node {
+	switch n := node.(type) {
+	case *ListNode:
+		return n.Nodes
+	case *UnionNode:
+		nodes := make([]Node, len(n.Nodes))
+		for i, elem := range n.Nodes {
+			nodes[i] = elem
+		}
+		return nodes
+	case *FilterNode:
+		if n.Expr == nil {
+			return nil
+		}
+		return []Node{n.Expr}
+	case *BinaryOpNode:
+		return []Node{n.Left, n.Right}
+	case *UnaryOpNode:
+		return []Node{n.Node}
+	case *ParenNode:
+		return []Node{n.Node}
+	case *FunctionCallNode:
+		return n.Args
+	default:
+		return nil
+	}
+}
+
+// Walk traverses a JSONPath parse tree in depth-first order: it starts by
+// calling visitor.Visit(node); node must not be nil. If the visitor w
+// returned by visitor.Visit(node) is not nil, Walk is invoked recursively
+// with visitor w for each of the children of node, followed by a call of
+// w.Visit(nil).
+func Walk(node Node, visitor Visitor) {
+	if node == nil {
+		return
+	}
+	v := visitor.Visit(node)
+	if v == nil {
+		return
+	}
+	for _, child := range children(node) {
+		Walk(child, v)
+	}
+	v.Visit(nil)
+}
+
+// inspector implements Visitor by calling f for each visited node, and
+// stopping the walk beneath any node for which f returns false.
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a JSONPath parse tree in depth-first order: it starts
+// by calling f(node); node must not be nil. If f returns true, Inspect
+// invokes f recursively for each of the children of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(node, inspector(f))
+}
+
+// Rewrite traverses a copy of the parse tree rooted at node in depth-first
+// order, replacing each node n with f(n) before descending into n's
+// (possibly already rewritten) children. The original tree rooted at node
+// is left untouched, since Rewrite operates on a single Copy() of the
+// entire tree taken up front. If f returns nil (or any node other than a
+// *ListNode) for a branch of a UnionNode, that branch is dropped from the
+// union.
+func Rewrite(node Node, f func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+	return rewrite(node.Copy(), f)
+}
+
+// rewrite does the work of Rewrite over a subtree that has already been
+// copied, so it mutates nodes in place instead of copying again at every
+// level of the recursion.
+func rewrite(node Node, f func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+	node = f(node)
+	if node == nil {
+		return nil
+	}
+	switch n := node.(type) {
+	case *ListNode:
+		for i, child := range n.Nodes {
+			n.Nodes[i] = rewrite(child, f)
+		}
+	case *UnionNode:
+		nodes := n.Nodes[:0]
+		for _, child := range n.Nodes {
+			rewritten, ok := rewrite(child, f).(*ListNode)
+			if !ok {
+				// f pruned this branch by returning nil (or a non-ListNode);
+				// drop it from the union instead of panicking.
+				continue
+			}
+			nodes = append(nodes, rewritten)
+		}
+		n.Nodes = nodes
+	case *FilterNode:
+		if n.Expr != nil {
+			n.Expr = rewrite(n.Expr, f)
+		}
+	case *BinaryOpNode:
+		n.Left = rewrite(n.Left, f)
+		n.Right = rewrite(n.Right, f)
+	case *UnaryOpNode:
+		n.Node = rewrite(n.Node, f)
+	case *ParenNode:
+		n.Node = rewrite(n.Node, f)
+	case *FunctionCallNode:
+		for i, arg := range n.Args {
+			n.Args[i] = rewrite(arg, f)
+		}
+	}
+	return node
+}
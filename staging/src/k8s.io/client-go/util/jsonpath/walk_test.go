@@ -0,0 +1,109 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonpath
+
+import "testing"
+
+func TestInspectVisitsAllNodes(t *testing.T) {
+	root := newList(0, nil)
+	root.append(newField(1, "a", nil))
+	root.append(newField(2, "b", nil))
+
+	var seen []NodeType
+	Inspect(root, func(n Node) bool {
+		if n != nil {
+			seen = append(seen, n.Type())
+		}
+		return true
+	})
+
+	want := []NodeType{NodeList, NodeField, NodeField}
+	if len(seen) != len(want) {
+		t.Fatalf("Inspect visited %v, want %v", seen, want)
+	}
+	for i, ty := range want {
+		if seen[i] != ty {
+			t.Errorf("node %d: got %s, want %s", i, seen[i], ty)
+		}
+	}
+}
+
+func TestRewriteLeavesOriginalUntouched(t *testing.T) {
+	root := newList(0, nil)
+	root.append(newField(1, "a", nil))
+
+	rewritten := Rewrite(root, func(n Node) Node {
+		if f, ok := n.(*FieldNode); ok {
+			f.Value = f.Value + "!"
+		}
+		return n
+	}).(*ListNode)
+
+	if root.Nodes[0].(*FieldNode).Value != "a" {
+		t.Fatalf("Rewrite mutated the original tree: got %q, want %q", root.Nodes[0].(*FieldNode).Value, "a")
+	}
+	if rewritten.Nodes[0].(*FieldNode).Value != "a!" {
+		t.Fatalf("Rewrite result not transformed: got %q, want %q", rewritten.Nodes[0].(*FieldNode).Value, "a!")
+	}
+}
+
+// TestRewritePruneUnionBranch is a regression test: f returning nil for a
+// *ListNode child of a UnionNode (the documented way to eliminate a dead
+// branch) used to panic with a failed type assertion instead of dropping
+// the branch.
+func TestRewritePruneUnionBranch(t *testing.T) {
+	keep := newList(0, nil)
+	keep.append(newField(1, "a", nil))
+	drop := newList(0, nil)
+	drop.append(newField(2, "b", nil))
+
+	union := newUnion(0, []*ListNode{keep, drop}, nil)
+
+	rewritten := Rewrite(union, func(n Node) Node {
+		if l, ok := n.(*ListNode); ok && len(l.Nodes) > 0 {
+			if f, ok := l.Nodes[0].(*FieldNode); ok && f.Value == "b" {
+				return nil
+			}
+		}
+		return n
+	}).(*UnionNode)
+
+	if len(rewritten.Nodes) != 1 {
+		t.Fatalf("Rewrite() did not prune the dead branch: got %d branches, want 1", len(rewritten.Nodes))
+	}
+	if rewritten.Nodes[0].Nodes[0].(*FieldNode).Value != "a" {
+		t.Fatalf("Rewrite() kept the wrong branch")
+	}
+}
+
+func TestRewriteFilterExpr(t *testing.T) {
+	filter := newFilter(0, newBinaryOp(1, "==", newField(2, "a", nil), newInt(3, 1, nil), nil), nil)
+
+	rewritten := Rewrite(filter, func(n Node) Node {
+		if i, ok := n.(*IntNode); ok {
+			i.Value = 2
+		}
+		return n
+	}).(*FilterNode)
+
+	if got := rewritten.Expr.(*BinaryOpNode).Right.(*IntNode).Value; got != 2 {
+		t.Fatalf("Rewrite() did not descend into FilterNode.Expr: got %d, want 2", got)
+	}
+	if got := filter.Expr.(*BinaryOpNode).Right.(*IntNode).Value; got != 1 {
+		t.Fatalf("Rewrite mutated the original FilterNode.Expr: got %d, want 1", got)
+	}
+}